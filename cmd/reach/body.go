@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// readRequestBody resolves the --data value into a request body reader.
+// A value prefixed with '@' is treated as a path to read the body from,
+// matching curl's convention.
+func readRequestBody(data string) (io.Reader, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(data, "@") {
+		contents, err := os.ReadFile(data[1:])
+		if err != nil {
+			return nil, fmt.Errorf("unable to read data file '%s': %w", data[1:], err)
+		}
+		return bytes.NewReader(contents), nil
+	}
+
+	return strings.NewReader(data), nil
+}
+
+// parseHeaderFlag splits a "Name: value" flag value into its parts.
+func parseHeaderFlag(raw string) (string, string, error) {
+	parts := strings.SplitN(raw, ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("header '%s' is not in 'Name: value' form", raw)
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), nil
+}
+
+// printBodyPreview reads up to maxBytes of response.Body and renders it
+// according to the response's content type: JSON is pretty-printed, text
+// types are printed as-is, and anything else is hex-dumped.
+func printBodyPreview(response *http.Response, maxBytes int) {
+	limited := io.LimitReader(response.Body, int64(maxBytes)+1)
+	raw, err := io.ReadAll(limited)
+	if err != nil {
+		handleTransportError("Could Not Read Response Body", err)
+	}
+
+	truncated := len(raw) > maxBytes
+	if truncated {
+		raw = raw[:maxBytes]
+	}
+
+	fmt.Println()
+	contentType := response.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(contentType, "json"):
+		fmt.Println(prettyJSON(raw))
+	case strings.HasPrefix(contentType, "text/"), strings.Contains(contentType, "xml"), strings.Contains(contentType, "javascript"):
+		fmt.Println(string(raw))
+	default:
+		fmt.Print(hex.Dump(raw))
+	}
+
+	if truncated {
+		fmt.Printf("... (truncated at %d bytes)\n", maxBytes)
+	}
+}
+
+// prettyJSON re-indents raw JSON for display, falling back to the raw
+// bytes unchanged if they don't parse.
+func prettyJSON(raw []byte) string {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, raw, "", "  "); err != nil {
+		return string(raw)
+	}
+	return indented.String()
+}
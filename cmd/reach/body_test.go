@@ -0,0 +1,74 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseHeaderFlag(t *testing.T) {
+	name, value, err := parseHeaderFlag("X-Test: hello world")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "X-Test" || value != "hello world" {
+		t.Errorf("got (%q, %q), want (%q, %q)", name, value, "X-Test", "hello world")
+	}
+
+	if _, _, err := parseHeaderFlag("no-colon-here"); err == nil {
+		t.Error("expected error for header value with no colon, got nil")
+	}
+}
+
+func TestReadRequestBody(t *testing.T) {
+	reader, err := readRequestBody("")
+	if err != nil || reader != nil {
+		t.Errorf("readRequestBody(\"\") = (%v, %v), want (nil, nil)", reader, err)
+	}
+
+	reader, err = readRequestBody("hello")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("unexpected error reading body: %v", err)
+	}
+	if string(raw) != "hello" {
+		t.Errorf("got body %q, want %q", raw, "hello")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "body.txt")
+	if err := os.WriteFile(path, []byte("from file"), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+	reader, err = readRequestBody("@" + path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw, err = io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("unexpected error reading body: %v", err)
+	}
+	if string(raw) != "from file" {
+		t.Errorf("got body %q, want %q", raw, "from file")
+	}
+
+	if _, err := readRequestBody("@" + filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("expected error reading missing data file, got nil")
+	}
+}
+
+func TestPrettyJSON(t *testing.T) {
+	got := prettyJSON([]byte(`{"a":1}`))
+	want := "{\n  \"a\": 1\n}"
+	if got != want {
+		t.Errorf("prettyJSON = %q, want %q", got, want)
+	}
+
+	if got := prettyJSON([]byte("not json")); got != "not json" {
+		t.Errorf("prettyJSON fallback = %q, want %q", got, "not json")
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/proxy"
+)
+
+// resolveOverride is a single curl-style --resolve entry: connections to
+// Host:Port are redirected to Addr:Port instead of going through DNS.
+type resolveOverride struct {
+	Host string
+	Port string
+	Addr string
+}
+
+// parseResolveFlag parses a "host:port:addr" --resolve value.
+func parseResolveFlag(raw string) (resolveOverride, error) {
+	parts := strings.SplitN(raw, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return resolveOverride{}, fmt.Errorf("--resolve value '%s' is not in 'host:port:addr' form", raw)
+	}
+	return resolveOverride{Host: parts[0], Port: parts[1], Addr: parts[2]}, nil
+}
+
+// buildProxyFunc returns an http.Transport.Proxy func for the --proxy
+// flag. With no --proxy it returns http.ProxyFromEnvironment (which
+// honors HTTP_PROXY/HTTPS_PROXY/NO_PROXY); for a socks5 proxy it returns
+// nil, since that proxy is dialed through buildDialContext instead.
+func buildProxyFunc(rawProxy string) (func(*http.Request) (*url.URL, error), error) {
+	if rawProxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(rawProxy)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse --proxy value '%s': %w", rawProxy, err)
+	}
+
+	printTransportProgress(fmt.Sprintf("Using proxy %s", proxyURL))
+	fmt.Println()
+
+	if proxyURL.Scheme == "socks5" {
+		// http.Transport has no native SOCKS5 support, so dial through
+		// it directly and ignore the resulting func(*http.Request) path.
+		return nil, nil
+	}
+
+	return http.ProxyURL(proxyURL), nil
+}
+
+// buildDialContext returns the DialContext to install on the Transport,
+// applying --resolve overrides and routing through a SOCKS5 --proxy when
+// one was given.
+func buildDialContext(rawProxy string, overrides []resolveOverride, resolver *net.Resolver) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	baseDialer := &net.Dialer{Resolver: resolver}
+
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		addr = applyResolveOverrides(addr, overrides)
+		return baseDialer.DialContext(ctx, network, addr)
+	}
+
+	if rawProxy == "" {
+		return dial, nil
+	}
+
+	proxyURL, err := url.Parse(rawProxy)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse --proxy value '%s': %w", rawProxy, err)
+	}
+	if proxyURL.Scheme != "socks5" {
+		return dial, nil
+	}
+
+	socksDialer, err := proxy.FromURL(proxyURL, baseDialer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure SOCKS5 proxy '%s': %w", rawProxy, err)
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		addr = applyResolveOverrides(addr, overrides)
+		return socksDialer.Dial(network, addr)
+	}, nil
+}
+
+// applyResolveOverrides rewrites addr (host:port) to the configured
+// override address when one matches, leaving the port unchanged.
+func applyResolveOverrides(addr string, overrides []resolveOverride) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	for _, o := range overrides {
+		if o.Host == host && o.Port == port {
+			printTransportProgress(fmt.Sprintf("Resolved %s:%s via override -> %s", host, port, o.Addr))
+			fmt.Println()
+			return net.JoinHostPort(o.Addr, port)
+		}
+	}
+	return addr
+}
+
+// buildResolver builds a net.Resolver that sends DNS queries through
+// dnsServer instead of the system default, when one is configured.
+func buildResolver(dnsServer string) *net.Resolver {
+	if dnsServer == "" {
+		return net.DefaultResolver
+	}
+
+	if !strings.Contains(dnsServer, ":") {
+		dnsServer = net.JoinHostPort(dnsServer, "53")
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, dnsServer)
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net"
+	"testing"
+)
+
+func TestParseResolveFlag(t *testing.T) {
+	got, err := parseResolveFlag("example.com:443:127.0.0.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := resolveOverride{Host: "example.com", Port: "443", Addr: "127.0.0.1"}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	badValues := []string{"", "example.com", "example.com:443", "example.com::127.0.0.1", ":443:127.0.0.1"}
+	for _, raw := range badValues {
+		if _, err := parseResolveFlag(raw); err == nil {
+			t.Errorf("parseResolveFlag(%q): expected error, got nil", raw)
+		}
+	}
+}
+
+func TestApplyResolveOverrides(t *testing.T) {
+	overrides := []resolveOverride{{Host: "example.com", Port: "443", Addr: "127.0.0.1"}}
+
+	got := applyResolveOverrides("example.com:443", overrides)
+	if want := net.JoinHostPort("127.0.0.1", "443"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := applyResolveOverrides("other.com:443", overrides); got != "other.com:443" {
+		t.Errorf("unmatched addr got rewritten to %q", got)
+	}
+
+	if got := applyResolveOverrides("not-a-host-port", overrides); got != "not-a-host-port" {
+		t.Errorf("unparsable addr got rewritten to %q", got)
+	}
+}
+
+func TestBuildResolver(t *testing.T) {
+	if r := buildResolver(""); r != net.DefaultResolver {
+		t.Errorf("buildResolver(\"\") = %v, want net.DefaultResolver", r)
+	}
+
+	r := buildResolver("9.9.9.9")
+	if r == net.DefaultResolver || !r.PreferGo || r.Dial == nil {
+		t.Errorf("buildResolver(%q) did not return a custom resolver: %+v", "9.9.9.9", r)
+	}
+}
+
+func TestBuildProxyFuncDefault(t *testing.T) {
+	fn, err := buildProxyFunc("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fn == nil {
+		t.Error("expected a non-nil default proxy func")
+	}
+}
+
+func TestBuildProxyFuncInvalid(t *testing.T) {
+	if _, err := buildProxyFunc("http://%zz"); err == nil {
+		t.Error("expected error for unparsable --proxy value, got nil")
+	}
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+)
+
+var opts struct {
+	NoColor      bool   `short:"c" long:"nocolor" description:"Print output without colors."`
+	Timeout      int    `long:"timeout" default:"15" description:"HTTP request timeout in seconds"`
+	MaxRedirects int    `long:"maxredirects" default:"20" description:"Maximum redirects to follow"`
+	Help         bool   `long:"help" description:"Display usage instructions"`
+	Version      bool   `long:"version" description:"Display version and license information"`
+	TLSInfo      bool   `long:"tls-info" description:"Print negotiated TLS parameters and certificate chain summary"`
+	Insecure     bool   `long:"insecure" description:"Skip TLS certificate verification"`
+	MinTLSVer    string `long:"min-tls-version" description:"Minimum TLS version to negotiate (1.0, 1.1, 1.2, 1.3)"`
+	HTTP2        bool   `long:"http2" description:"Require HTTP/2 to be negotiated; fail if the server falls back to HTTP/1.1"`
+
+	Method          string   `long:"method" default:"HEAD" description:"HTTP method to use"`
+	Headers         []string `short:"H" long:"header" description:"Header to send, as 'Name: value' (repeatable)"`
+	Data            string   `short:"d" long:"data" description:"Request body, or @file to read the body from a file"`
+	UserAgent       string   `long:"user-agent" description:"Value for the User-Agent header"`
+	Cookie          string   `long:"cookie" description:"Value for the Cookie header"`
+	BasicAuth       string   `long:"basic-auth" description:"Credentials for HTTP basic auth, as 'user:pass'"`
+	MaxBodyBytes    int      `long:"max-body-bytes" default:"2048" description:"Maximum response body bytes to preview"`
+	AutoFallbackGet bool     `long:"auto-fallback-get" description:"Retry with GET if HEAD returns 405/501"`
+
+	Timing     bool `long:"timing" description:"Print a per-phase timing summary (DNS/Connect/TLS/TTFB/Total)"`
+	TimingJSON bool `long:"timing-json" description:"Print the timing summary as JSON"`
+
+	Proxy     string   `long:"proxy" description:"HTTP/HTTPS/SOCKS5 proxy URL (defaults to HTTP_PROXY/HTTPS_PROXY/NO_PROXY env vars)"`
+	Resolve   []string `long:"resolve" description:"Override DNS for host:port:addr (repeatable)"`
+	DNSServer string   `long:"dns-server" description:"Send DNS queries through this resolver instead of the system default"`
+
+	Cookies        bool   `long:"cookies" description:"Keep a cookie jar across redirects, replaying Set-Cookie from intermediate hops"`
+	RedirectPolicy string `long:"redirect-policy" default:"any" description:"Redirect handling: strict, same-host, or any"`
+
+	Via         string `long:"via" description:"Probe the URL from a remote reach tunnel serve relay, as user@host:port"`
+	ViaToken    string `long:"via-token" description:"Shared token for authenticating to the --via relay"`
+	ViaPin      string `long:"via-pin" description:"SHA-256 fingerprint of the --via relay's certificate, as printed by tunnel serve"`
+	ViaInsecure bool   `long:"via-insecure" description:"Skip certificate verification on the --via control connection"`
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "tunnel" {
+		runTunnelCommand(os.Args[2:])
+		return
+	}
+
+	args := parseArgs()
+
+	if opts.Help {
+		printHelp()
+		os.Exit(0)
+	} else if opts.Version {
+		printVersion()
+		os.Exit(0)
+	}
+
+	if opts.Via != "" {
+		probeViaTunnel(args[0])
+		return
+	}
+
+	getURL(args[0])
+}
+
+func parseArgs() []string {
+	p := flags.NewParser(&opts, 0)
+
+	args, err := p.Parse()
+
+	if err != nil || len(args) < 1 {
+		printHelp()
+		os.Exit(0)
+	}
+	return args
+}
+
+func getURL(targetURL string) {
+	/* Attempts to get url while explicitly handling redirects
+
+	Makes a custom Transport (roundtripper) to specify timeout
+
+	Validates URL and sends request, which uses trace to follow
+		step-by-step through the request process
+
+	If a redirect response is returned it is printed then followed
+	*/
+
+	minTLSVersion, err := parseMinTLSVersion(opts.MinTLSVer)
+	if err != nil {
+		handleTransportError("Invalid Flag", err)
+	}
+
+	var resolveOverrides []resolveOverride
+	for _, raw := range opts.Resolve {
+		override, err := parseResolveFlag(raw)
+		if err != nil {
+			handleTransportError("Invalid Flag", err)
+		}
+		resolveOverrides = append(resolveOverrides, override)
+	}
+
+	resolver := buildResolver(opts.DNSServer)
+
+	proxyFunc, err := buildProxyFunc(opts.Proxy)
+	if err != nil {
+		handleTransportError("Invalid Flag", err)
+	}
+
+	dialContext, err := buildDialContext(opts.Proxy, resolveOverrides, resolver)
+	if err != nil {
+		handleTransportError("Invalid Flag", err)
+	}
+
+	var roundTripper = &http.Transport{
+		ResponseHeaderTimeout: time.Duration(opts.Timeout) * time.Second,
+		ForceAttemptHTTP2:     true,
+		Proxy:                 proxyFunc,
+		DialContext:           dialContext,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: opts.Insecure,
+			MinVersion:         minTLSVersion,
+		},
+	}
+
+	policy, err := parseRedirectPolicy(opts.RedirectPolicy)
+	if err != nil {
+		handleTransportError("Invalid Flag", err)
+	}
+
+	jar, err := buildCookieJar(opts.Cookies)
+	if err != nil {
+		handleTransportError("Could Not Build Cookie Jar", err)
+	}
+
+	targetURL = normalizeURLScheme(targetURL)
+
+	if !verifyURL(targetURL) {
+		handleTransportError("Invalid URL", errors.New("Unable to parse '"+targetURL+"'"))
+	}
+	nextURL, err := url.Parse(targetURL)
+	if err != nil {
+		handleTransportError("Invalid URL", err)
+	}
+
+	method := strings.ToUpper(opts.Method)
+	data := opts.Data
+	var timings []*requestTiming
+	var chain []string
+	visited := map[string]bool{}
+
+	for i := 0; i < opts.MaxRedirects; i++ {
+
+		key := visitKey(method, nextURL)
+		if visited[key] {
+			handleTransportError("Redirect Cycle", fmt.Errorf("'%s' was already requested in this chain", nextURL))
+		}
+		visited[key] = true
+		chain = append(chain, fmt.Sprintf("%s %s", method, nextURL))
+
+		response, rt, err := sendRequest(roundTripper, jar, method, nextURL, data)
+		if err != nil {
+			switch err := err.(type) {
+			case net.Error:
+				if err.Timeout() {
+					handleTransportError("Request Failed", errors.New("Request timed out before a response was received."))
+				}
+			default:
+				handleTransportError("Request Failed", err)
+
+			}
+			os.Exit(0)
+		}
+
+		if opts.AutoFallbackGet && method == "HEAD" && (response.StatusCode == 405 || response.StatusCode == 501) {
+			response.Body.Close()
+			method = "GET"
+			response, rt, err = sendRequest(roundTripper, jar, method, nextURL, data)
+			if err != nil {
+				handleTransportError("Request Failed", err)
+			}
+		}
+
+		timings = append(timings, rt)
+
+		if opts.HTTP2 && response.TLS != nil && response.TLS.NegotiatedProtocol != "h2" {
+			handleTransportError("HTTP/2 Required", fmt.Errorf("server negotiated '%s' instead of h2", alpnOrNone(response.TLS.NegotiatedProtocol)))
+		}
+
+		printResponseInfo(response)
+		fmt.Printf(" (%.0fms)", rt.phases().Total)
+
+		if opts.TLSInfo {
+			printTLSInfo(response.TLS)
+		}
+
+		if response.StatusCode/100 == 3 {
+			location := response.Header.Get("Location")
+			response.Body.Close()
+			fmt.Println()
+
+			redirectURL, err := resolveRedirectLocation(nextURL, location)
+			if err != nil {
+				handleTransportError("Invalid Redirect", err)
+			}
+			if err := checkRedirectPolicy(policy, nextURL, redirectURL); err != nil {
+				handleTransportError("Redirect Refused", err)
+			}
+
+			method, data = rewriteRedirectMethod(response.StatusCode, method, data)
+			nextURL = redirectURL
+		} else {
+			if method != "HEAD" {
+				printBodyPreview(response, opts.MaxBodyBytes)
+			}
+			response.Body.Close()
+			break
+		}
+	}
+	fmt.Println()
+
+	if len(chain) > 1 {
+		fmt.Println("Redirect chain:")
+		for _, hop := range chain {
+			fmt.Printf("  %s\n", hop)
+		}
+	}
+
+	if opts.TimingJSON {
+		printTimingJSON(timings)
+	} else if opts.Timing {
+		printTimingSummary(timings)
+	}
+}
+
+// sendRequest builds a request for method and targetURL carrying the
+// headers, body, and auth configured via flags, then sends it through
+// roundTripper with a per-request trace attached. When jar is non-nil its
+// cookies are attached to the request and any Set-Cookie in the response
+// is stored back into it. It returns the response alongside the timing
+// data recorded for this single hop.
+func sendRequest(roundTripper http.RoundTripper, jar http.CookieJar, method string, targetURL *url.URL, data string) (*http.Response, *requestTiming, error) {
+	body, err := readRequestBody(data)
+	if err != nil {
+		handleTransportError("Invalid Flag", err)
+	}
+
+	request, err := http.NewRequest(method, targetURL.String(), body)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, raw := range opts.Headers {
+		name, value, err := parseHeaderFlag(raw)
+		if err != nil {
+			handleTransportError("Invalid Flag", err)
+		}
+		request.Header.Add(name, value)
+	}
+
+	if opts.UserAgent != "" {
+		request.Header.Set("User-Agent", opts.UserAgent)
+	}
+	if opts.Cookie != "" {
+		request.Header.Set("Cookie", opts.Cookie)
+	}
+	if opts.BasicAuth != "" {
+		user, pass, ok := strings.Cut(opts.BasicAuth, ":")
+		if !ok {
+			handleTransportError("Invalid Flag", errors.New("--basic-auth must be in 'user:pass' form"))
+		}
+		request.SetBasicAuth(user, pass)
+	}
+
+	if jar != nil {
+		for _, c := range jar.Cookies(targetURL) {
+			request.AddCookie(c)
+		}
+	}
+
+	rt := &requestTiming{URL: targetURL.String(), Start: time.Now()}
+	request = request.WithContext(httptrace.WithClientTrace(request.Context(), newClientTrace(rt)))
+
+	response, err := roundTripper.RoundTrip(request)
+	rt.End = time.Now()
+
+	if err == nil && jar != nil {
+		jar.SetCookies(targetURL, response.Cookies())
+	}
+
+	return response, rt, err
+}
+
+// normalizeURLScheme prepends "http://" to targetURL when it has neither
+// an http:// nor https:// scheme, the same bare-hostname convenience
+// getURL has always offered.
+func normalizeURLScheme(targetURL string) string {
+	if !strings.HasPrefix(targetURL, "https://") && !strings.HasPrefix(targetURL, "http://") {
+		return "http://" + targetURL
+	}
+	return targetURL
+}
+
+func verifyURL(u string) bool {
+	parsed, err := url.Parse(u)
+	if err != nil ||
+		parsed.Scheme == "" ||
+		parsed.Host == "" ||
+		(parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return false
+	}
+	return true
+}
+
+func printClear() {
+	// If this doesn't work just make it longer
+	fmt.Print("\r                                   \r")
+}
+
+func printTransportProgress(name string) {
+	printClear()
+	fmt.Printf("%v", name)
+}
+
+func handleTransportError(name string, err error) {
+	printClear()
+	if opts.NoColor {
+		fmt.Printf("%s : %s", name, err.Error())
+	} else {
+		fmt.Printf("\x1b[91m%s:\x1b[0m %s", name, err.Error())
+	}
+	os.Exit(0)
+}
+
+func printResponseInfo(response *http.Response) {
+	var bg int
+	var fg int
+	var add string
+
+	printClear()
+	if opts.NoColor {
+		fmt.Printf("%s %s", response.Status, add)
+	} else {
+		switch response.StatusCode / 100 {
+		case 2:
+			bg = 102 // Green
+			fg = 30  // Black
+		case 3:
+			bg = 106 // Blue
+			fg = 30  // Black
+			add = fmt.Sprintf("-> %s", response.Header.Get("Location"))
+		case 4:
+			bg = 101 // Red
+			fg = 30  // Black
+		case 5:
+			bg = 105 // Purple
+			fg = 30  // Black
+		}
+
+		statusDescription := fmt.Sprintf("\x1b[107m\x1b[30m %s \x1b[0m", response.Status[4:]) // Black on White
+
+		fmt.Printf("\x1b[%dm\x1b[%dm %d \x1b[0m%s %s", bg, fg, response.StatusCode, statusDescription, add)
+	}
+}
+
+func printHelp() {
+	fmt.Println(`Usage: reach [OPTIONS] URL
+
+Options:
+  -c, --nocolor               Print output without colors
+  --maxredirects=REDIRECTS    Maximum redirects to follow [default: 20]
+  --timeout=SECONDS           HTTP request timeout in seconds [default: 15]
+  --tls-info                  Print negotiated TLS parameters and certificate chain summary
+  --insecure                  Skip TLS certificate verification
+  --min-tls-version=VERSION   Minimum TLS version to negotiate (1.0, 1.1, 1.2, 1.3)
+  --http2                     Require HTTP/2 to be negotiated; fail if the server falls back to HTTP/1.1
+  --method=METHOD             HTTP method to use [default: HEAD]
+  -H, --header=NAME:VALUE     Header to send (repeatable)
+  -d, --data=DATA             Request body, or @file to read the body from a file
+  --user-agent=AGENT          Value for the User-Agent header
+  --cookie=COOKIE             Value for the Cookie header
+  --basic-auth=USER:PASS      Credentials for HTTP basic auth
+  --max-body-bytes=BYTES      Maximum response body bytes to preview [default: 2048]
+  --auto-fallback-get         Retry with GET if HEAD returns 405/501
+  --timing                    Print a per-phase timing summary (DNS/Connect/TLS/TTFB/Total)
+  --timing-json               Print the timing summary as JSON
+  --proxy=URL                 HTTP/HTTPS/SOCKS5 proxy URL (defaults to HTTP_PROXY/HTTPS_PROXY/NO_PROXY)
+  --resolve=HOST:PORT:ADDR    Override DNS for host:port (repeatable)
+  --dns-server=ADDR           Send DNS queries through this resolver
+  --cookies                   Keep a cookie jar across redirects
+  --redirect-policy=POLICY    Redirect handling: strict, same-host, or any [default: any]
+  --via=USER@HOST:PORT        Probe the URL from a remote reach tunnel serve relay
+  --via-token=TOKEN           Shared token for authenticating to the --via relay
+  --via-pin=FINGERPRINT       SHA-256 fingerprint of the --via relay's certificate
+  --via-insecure              Skip certificate verification on the --via control connection
+  --help                      Display this help message
+  --version                   Display version and license info
+
+  reach tunnel serve --listen :4443 --token TOKEN
+                              Run a relay that --via can probe through
+  `)
+}
+
+func printVersion() {
+	fmt.Println(`reach 0.1
+Copyright (C) 2016 Free Software Foundation, Inc.
+License GPLv3+: GNU GPL version 3 or later <http://gnu.org/licenses/gpl.html>.
+This is free software: you are free to change and redistribute it.
+There is NO WARRANTY, to the extent permitted by law.
+
+Written by Steven Sawyer.`)
+}
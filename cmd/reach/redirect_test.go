@@ -0,0 +1,127 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestParseRedirectPolicy(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    redirectPolicy
+		wantErr bool
+	}{
+		{"", redirectPolicyAny, false},
+		{"strict", redirectPolicyStrict, false},
+		{"same-host", redirectPolicySameHost, false},
+		{"any", redirectPolicyAny, false},
+		{"bogus", "", true},
+	}
+
+	for _, c := range cases {
+		got, err := parseRedirectPolicy(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("parseRedirectPolicy(%q): expected error, got nil", c.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseRedirectPolicy(%q): unexpected error: %v", c.in, err)
+		}
+		if got != c.want {
+			t.Errorf("parseRedirectPolicy(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestCheckRedirectPolicy(t *testing.T) {
+	same, _ := url.Parse("https://example.com/a")
+	sameOther, _ := url.Parse("https://example.com/b")
+	cross, _ := url.Parse("https://other.com/a")
+
+	if err := checkRedirectPolicy(redirectPolicyStrict, same, sameOther); err == nil {
+		t.Error("strict policy: expected error, got nil")
+	}
+	if err := checkRedirectPolicy(redirectPolicySameHost, same, sameOther); err != nil {
+		t.Errorf("same-host policy same host: unexpected error: %v", err)
+	}
+	if err := checkRedirectPolicy(redirectPolicySameHost, same, cross); err == nil {
+		t.Error("same-host policy cross host: expected error, got nil")
+	}
+	if err := checkRedirectPolicy(redirectPolicyAny, same, cross); err != nil {
+		t.Errorf("any policy: unexpected error: %v", err)
+	}
+}
+
+func TestResolveRedirectLocation(t *testing.T) {
+	prev, _ := url.Parse("https://example.com/a/b")
+
+	got, err := resolveRedirectLocation(prev, "/c")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "https://example.com/c"; got.String() != want {
+		t.Errorf("got %q, want %q", got.String(), want)
+	}
+
+	got, err = resolveRedirectLocation(prev, "https://other.com/x")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "https://other.com/x"; got.String() != want {
+		t.Errorf("got %q, want %q", got.String(), want)
+	}
+
+	if _, err := resolveRedirectLocation(prev, "://bad"); err == nil {
+		t.Error("expected error for unparsable location, got nil")
+	}
+}
+
+func TestRewriteRedirectMethod(t *testing.T) {
+	cases := []struct {
+		status     int
+		method     string
+		body       string
+		wantMethod string
+		wantBody   string
+	}{
+		{http.StatusSeeOther, "POST", "payload", http.MethodGet, ""},
+		{http.StatusTemporaryRedirect, "POST", "payload", "POST", "payload"},
+		{http.StatusPermanentRedirect, "PUT", "payload", "PUT", "payload"},
+		{http.StatusFound, "POST", "payload", "POST", "payload"},
+		{http.StatusMovedPermanently, "GET", "", "GET", ""},
+	}
+
+	for _, c := range cases {
+		gotMethod, gotBody := rewriteRedirectMethod(c.status, c.method, c.body)
+		if gotMethod != c.wantMethod || gotBody != c.wantBody {
+			t.Errorf("rewriteRedirectMethod(%d, %q, %q) = (%q, %q), want (%q, %q)",
+				c.status, c.method, c.body, gotMethod, gotBody, c.wantMethod, c.wantBody)
+		}
+	}
+}
+
+func TestVisitKey(t *testing.T) {
+	u, _ := url.Parse("https://example.com/a#frag")
+	got := visitKey("get", u)
+	if want := "GET https://example.com/a"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestBuildCookieJar(t *testing.T) {
+	jar, err := buildCookieJar(false)
+	if err != nil || jar != nil {
+		t.Errorf("buildCookieJar(false) = (%v, %v), want (nil, nil)", jar, err)
+	}
+
+	jar, err = buildCookieJar(true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if jar == nil {
+		t.Error("buildCookieJar(true) returned nil jar")
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http/httptrace"
+	"time"
+)
+
+// requestTiming records the wall-clock timestamps of each phase of a
+// single request, as reported by an httptrace.ClientTrace. Durations are
+// derived from these once the request completes.
+type requestTiming struct {
+	URL string
+
+	Start        time.Time
+	DNSStart     time.Time
+	DNSDone      time.Time
+	ConnectStart time.Time
+	ConnectDone  time.Time
+	TLSStart     time.Time
+	TLSDone      time.Time
+	FirstByte    time.Time
+	End          time.Time
+}
+
+// newClientTrace builds an httptrace.ClientTrace that both drives the
+// existing progress output and records timestamps into rt.
+func newClientTrace(rt *requestTiming) *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		PutIdleConn: func(err error) {
+			if err != nil {
+				handleTransportError("Could Not Finish Connection", err)
+			} else {
+				printTransportProgress("Connection finished")
+			}
+		},
+
+		GotFirstResponseByte: func() {
+			rt.FirstByte = time.Now()
+			printTransportProgress("Receiving Response")
+		},
+
+		Got100Continue: func() {
+			printTransportProgress("Received 100 Response - Waiting... ")
+		},
+
+		DNSStart: func(i httptrace.DNSStartInfo) {
+			rt.DNSStart = time.Now()
+			printTransportProgress("Starting DNS Lookup")
+		},
+
+		DNSDone: func(i httptrace.DNSDoneInfo) {
+			rt.DNSDone = time.Now()
+			if i.Err != nil {
+				handleTransportError("DNS Lookup Failed", i.Err)
+			} else {
+				printTransportProgress("DNS Lookup Complete")
+				if opts.DNSServer != "" && len(i.Addrs) > 0 {
+					fmt.Println()
+					printTransportProgress(fmt.Sprintf("Resolved via %s -> %s", opts.DNSServer, i.Addrs[0].String()))
+				}
+			}
+		},
+
+		ConnectStart: func(network, addr string) {
+			rt.ConnectStart = time.Now()
+			printTransportProgress("Connection Started")
+		},
+
+		ConnectDone: func(network, addr string, err error) {
+			rt.ConnectDone = time.Now()
+			if err != nil {
+				handleTransportError("Connection Failed", err)
+			} else {
+				printTransportProgress("Connected - waiting for response...")
+			}
+		},
+
+		TLSHandshakeStart: func() {
+			rt.TLSStart = time.Now()
+		},
+
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			rt.TLSDone = time.Now()
+			if err != nil {
+				handleTransportError("TLS Handshake Failed", err)
+			} else {
+				printTransportProgress("TLS Handshake Complete.")
+			}
+		},
+	}
+}
+
+// durationMillis returns the duration from start to end in milliseconds,
+// or 0 if either timestamp was never recorded.
+func durationMillis(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return float64(end.Sub(start)) / float64(time.Millisecond)
+}
+
+type timingPhases struct {
+	URL     string  `json:"url"`
+	DNS     float64 `json:"dns_ms"`
+	Connect float64 `json:"connect_ms"`
+	TLS     float64 `json:"tls_ms"`
+	TTFB    float64 `json:"ttfb_ms"`
+	Total   float64 `json:"total_ms"`
+}
+
+func (rt *requestTiming) phases() timingPhases {
+	return timingPhases{
+		URL:     rt.URL,
+		DNS:     durationMillis(rt.DNSStart, rt.DNSDone),
+		Connect: durationMillis(rt.ConnectStart, rt.ConnectDone),
+		TLS:     durationMillis(rt.TLSStart, rt.TLSDone),
+		TTFB:    durationMillis(rt.Start, rt.FirstByte),
+		Total:   durationMillis(rt.Start, rt.End),
+	}
+}
+
+// printTimingSummary prints a curl-w/httpstat style phase breakdown for
+// each hop in timings.
+func printTimingSummary(timings []*requestTiming) {
+	fmt.Println()
+	fmt.Println("Timing:")
+	for _, rt := range timings {
+		p := rt.phases()
+		fmt.Printf("  %s\n", p.URL)
+		fmt.Printf("    DNS Lookup:    %8.2fms\n", p.DNS)
+		fmt.Printf("    TCP Connect:   %8.2fms\n", p.Connect)
+		fmt.Printf("    TLS Handshake: %8.2fms\n", p.TLS)
+		fmt.Printf("    TTFB:          %8.2fms\n", p.TTFB)
+		fmt.Printf("    Total:         %8.2fms\n", p.Total)
+	}
+}
+
+// printTimingJSON prints the same phase breakdown as printTimingSummary
+// but as a machine-readable JSON array, one entry per hop.
+func printTimingJSON(timings []*requestTiming) {
+	phases := make([]timingPhases, len(timings))
+	for i, rt := range timings {
+		phases[i] = rt.phases()
+	}
+
+	encoded, err := json.MarshalIndent(phases, "", "  ")
+	if err != nil {
+		handleTransportError("Could Not Encode Timing", err)
+	}
+	fmt.Println(string(encoded))
+}
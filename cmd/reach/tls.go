@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// certExpiryWarningDays is the threshold at or under which a certificate's
+// remaining validity is highlighted as a warning.
+const certExpiryWarningDays = 30
+
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+// parseMinTLSVersion converts a user-supplied version string (e.g. "1.2")
+// into the corresponding crypto/tls constant. Returns 0 (leave
+// TLSClientConfig.MinVersion unset) when version is empty, so Go's own
+// default minimum (TLS 1.2) applies instead of silently lowering it.
+func parseMinTLSVersion(version string) (uint16, error) {
+	switch strings.TrimSpace(version) {
+	case "":
+		return 0, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unrecognized TLS version '%s'", version)
+	}
+}
+
+// printTLSInfo prints the negotiated TLS parameters and certificate chain
+// summary for state. Certificates expiring within certExpiryWarningDays
+// are highlighted in red via printWarning.
+func printTLSInfo(state *tls.ConnectionState) {
+	if state == nil {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("TLS Version:   %s\n", tlsVersionNames[state.Version])
+	fmt.Printf("Cipher Suite:  %s\n", tls.CipherSuiteName(state.CipherSuite))
+	fmt.Printf("ALPN Protocol: %s\n", alpnOrNone(state.NegotiatedProtocol))
+	fmt.Printf("Server Name:   %s\n", state.ServerName)
+	fmt.Printf("OCSP Stapled:  %v\n", len(state.OCSPResponse) > 0)
+
+	for i, cert := range state.PeerCertificates {
+		printCertSummary(i, cert)
+	}
+}
+
+func alpnOrNone(proto string) string {
+	if proto == "" {
+		return "(none negotiated)"
+	}
+	return proto
+}
+
+func printCertSummary(index int, cert *x509.Certificate) {
+	daysLeft := int(time.Until(cert.NotAfter).Hours() / 24)
+
+	fmt.Printf("Certificate[%d]:\n", index)
+	fmt.Printf("  Subject:  %s\n", cert.Subject)
+	fmt.Printf("  Issuer:   %s\n", cert.Issuer)
+	if len(cert.DNSNames) > 0 {
+		fmt.Printf("  SAN:      %s\n", strings.Join(cert.DNSNames, ", "))
+	}
+	fmt.Printf("  Expires:  %s\n", cert.NotAfter.Format(time.RFC1123))
+
+	expiryLine := fmt.Sprintf("  Days Left: %d", daysLeft)
+	if daysLeft <= certExpiryWarningDays {
+		printWarning(expiryLine)
+	} else {
+		fmt.Println(expiryLine)
+	}
+}
+
+// printWarning prints line in the same bright red used for error output,
+// respecting --nocolor.
+func printWarning(line string) {
+	if opts.NoColor {
+		fmt.Println(line)
+	} else {
+		fmt.Printf("\x1b[91m%s\x1b[0m\n", line)
+	}
+}
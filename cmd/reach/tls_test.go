@@ -0,0 +1,48 @@
+package main
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestParseMinTLSVersion(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    uint16
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"1.0", tls.VersionTLS10, false},
+		{"1.1", tls.VersionTLS11, false},
+		{"1.2", tls.VersionTLS12, false},
+		{"1.3", tls.VersionTLS13, false},
+		{"  1.2  ", tls.VersionTLS12, false},
+		{"1.4", 0, true},
+		{"garbage", 0, true},
+	}
+
+	for _, c := range cases {
+		got, err := parseMinTLSVersion(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("parseMinTLSVersion(%q): expected error, got nil", c.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseMinTLSVersion(%q): unexpected error: %v", c.in, err)
+		}
+		if got != c.want {
+			t.Errorf("parseMinTLSVersion(%q) = %d, want %d", c.in, got, c.want)
+		}
+	}
+}
+
+func TestAlpnOrNone(t *testing.T) {
+	if got := alpnOrNone(""); got != "(none negotiated)" {
+		t.Errorf("alpnOrNone(\"\") = %q, want %q", got, "(none negotiated)")
+	}
+	if got := alpnOrNone("h2"); got != "h2" {
+		t.Errorf("alpnOrNone(\"h2\") = %q, want %q", got, "h2")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	flags "github.com/jessevdk/go-flags"
+
+	"reach/tunnel"
+)
+
+// tunnelServeOpts holds the flags for `reach tunnel serve`.
+var tunnelServeOpts struct {
+	Listen  string `long:"listen" default:":4443" description:"Address to listen on"`
+	Token   string `long:"token" required:"true" description:"Shared token clients must present to use this relay"`
+	Timeout int    `long:"timeout" default:"15" description:"Per-connection and relayed-request timeout in seconds"`
+}
+
+// runTunnelCommand dispatches `reach tunnel <subcommand>`.
+func runTunnelCommand(args []string) {
+	if len(args) < 1 || args[0] != "serve" {
+		fmt.Println("Usage: reach tunnel serve --listen :4443 --token TOKEN")
+		os.Exit(0)
+	}
+
+	p := flags.NewParser(&tunnelServeOpts, 0)
+	if _, err := p.ParseArgs(args[1:]); err != nil {
+		fmt.Println("Usage: reach tunnel serve --listen :4443 --token TOKEN")
+		os.Exit(0)
+	}
+
+	cert, err := tunnel.GenerateSelfSignedCert()
+	if err != nil {
+		handleTransportError("Could Not Start Relay", err)
+	}
+
+	fingerprint, err := tunnel.CertFingerprint(cert)
+	if err != nil {
+		handleTransportError("Could Not Start Relay", err)
+	}
+
+	fmt.Printf("Listening on %s\n", tunnelServeOpts.Listen)
+	fmt.Printf("Certificate fingerprint (pass to --via-pin): %s\n", fingerprint)
+	timeout := time.Duration(tunnelServeOpts.Timeout) * time.Second
+	if err := tunnel.Serve(tunnelServeOpts.Listen, tunnelServeOpts.Token, cert, timeout); err != nil {
+		handleTransportError("Relay Failed", err)
+	}
+}
+
+// probeViaTunnel performs targetURL's request through the relay named by
+// --via instead of sending it directly, printing the remote's trace
+// events and final status the same way a direct request would.
+func probeViaTunnel(targetURL string) {
+	hostPort, err := tunnel.ParseVia(opts.Via)
+	if err != nil {
+		handleTransportError("Invalid Flag", err)
+	}
+
+	targetURL = normalizeURLScheme(targetURL)
+
+	timeout := time.Duration(opts.Timeout) * time.Second
+	response, err := tunnel.Probe(hostPort, opts.ViaToken, strings.ToUpper(opts.Method), targetURL, opts.ViaPin, opts.ViaInsecure, timeout, func(name string) {
+		printTransportProgress(name)
+	})
+	if err != nil {
+		handleTransportError("Relay Probe Failed", err)
+	}
+
+	printClear()
+	add := ""
+	if response.Location != "" {
+		add = fmt.Sprintf("-> %s", response.Location)
+	}
+	fmt.Printf("%s %s\n", response.Status, add)
+}
@@ -0,0 +1,60 @@
+package tunnel
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// GenerateSelfSignedCert builds an ephemeral TLS certificate for reach
+// tunnel serve. It is regenerated on every startup, so the operator must
+// pass its fingerprint (printed at startup) to reach --via as --via-pin
+// for the control connection to be authenticated; see CertFingerprint.
+func GenerateSelfSignedCert() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("generating relay key: %w", err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("generating relay certificate serial: %w", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: "reach tunnel relay"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	derCert, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("creating relay certificate: %w", err)
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{derCert},
+		PrivateKey:  key,
+	}, nil
+}
+
+// CertFingerprint returns the hex-encoded SHA-256 digest of cert's leaf
+// certificate, for the operator to pin with --via-pin.
+func CertFingerprint(cert tls.Certificate) (string, error) {
+	if len(cert.Certificate) == 0 {
+		return "", fmt.Errorf("certificate has no leaf to fingerprint")
+	}
+	digest := sha256.Sum256(cert.Certificate[0])
+	return hex.EncodeToString(digest[:]), nil
+}
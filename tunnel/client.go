@@ -0,0 +1,116 @@
+package tunnel
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// verifyPinnedFingerprint returns a tls.Config.VerifyConnection callback
+// that rejects the handshake unless the leaf certificate's SHA-256
+// fingerprint matches pin.
+func verifyPinnedFingerprint(pin string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("relay presented no certificate")
+		}
+		digest := sha256.Sum256(cs.PeerCertificates[0].Raw)
+		if hex.EncodeToString(digest[:]) != pin {
+			return fmt.Errorf("relay certificate fingerprint does not match --via-pin")
+		}
+		return nil
+	}
+}
+
+// ParseVia splits a "user@host:port" --via value into its host:port, the
+// part this package actually dials. The user segment exists only for the
+// operator's own bookkeeping (e.g. distinguishing which relay host they
+// mean) and is not used in the protocol itself.
+func ParseVia(via string) (hostPort string, err error) {
+	_, hostPort, found := strings.Cut(via, "@")
+	if !found {
+		hostPort = via
+	}
+	if hostPort == "" {
+		return "", fmt.Errorf("--via value '%s' has no host:port", via)
+	}
+	return hostPort, nil
+}
+
+// Probe opens a TLS control connection to the relay at hostPort, forwards
+// a single method/url probe authenticated by token, and returns the
+// remote's final response summary. onEvent is called, in order, for each
+// trace event the remote observes while performing the probe. timeout
+// bounds the dial plus the entire exchange, so a relay that never
+// answers can't hang the caller indefinitely.
+//
+// The relay's self-signed certificate is regenerated on every `tunnel
+// serve` start, so the connection is authenticated by comparing the
+// leaf certificate's SHA-256 fingerprint against pin (the value printed
+// by `tunnel serve` at startup). Passing insecureSkipVerify instead
+// disables this check entirely; pin takes precedence when both are set.
+func Probe(hostPort string, token string, method string, url string, pin string, insecureSkipVerify bool, timeout time.Duration, onEvent func(name string)) (*ResponsePayload, error) {
+	if pin == "" && !insecureSkipVerify {
+		return nil, fmt.Errorf("refusing to connect to relay without --via-pin (from the relay's startup output) or --via-insecure")
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	if pin != "" {
+		tlsConfig.VerifyConnection = verifyPinnedFingerprint(pin)
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", hostPort, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to relay '%s': %w", hostPort, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	if err := writeFrame(conn, frameKindAuth, AuthPayload{Token: token}); err != nil {
+		return nil, err
+	}
+	if err := writeFrame(conn, frameKindProbe, ProbePayload{Method: method, URL: url}); err != nil {
+		return nil, err
+	}
+
+	for {
+		f, err := readFrame(conn)
+		if err != nil {
+			return nil, fmt.Errorf("reading from relay: %w", err)
+		}
+
+		switch f.Kind {
+		case frameKindEvent:
+			var event EventPayload
+			if err := json.Unmarshal(f.Payload, &event); err != nil {
+				return nil, err
+			}
+			if onEvent != nil {
+				onEvent(event.Name)
+			}
+
+		case frameKindResponse:
+			var response ResponsePayload
+			if err := json.Unmarshal(f.Payload, &response); err != nil {
+				return nil, err
+			}
+			return &response, nil
+
+		case frameKindError:
+			var errPayload ErrorPayload
+			if err := json.Unmarshal(f.Payload, &errPayload); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("relay: %s", errPayload.Message)
+
+		default:
+			return nil, fmt.Errorf("unexpected frame kind '%s' from relay", f.Kind)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Package tunnel implements the small framed protocol reach uses to probe
+// a URL through a remote relay: reach tunnel serve runs on a machine with
+// access to the target, and reach --via connects to it, forwards a single
+// request, and streams the remote's trace events back to the local
+// terminal.
+package tunnel
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// frameKind identifies the payload carried by a single frame.
+type frameKind string
+
+const (
+	frameKindAuth     frameKind = "auth"
+	frameKindProbe    frameKind = "probe"
+	frameKindEvent    frameKind = "event"
+	frameKindResponse frameKind = "response"
+	frameKindError    frameKind = "error"
+)
+
+// frame is the length-prefixed unit exchanged over the control
+// connection: a 4-byte big-endian length followed by a JSON body.
+type frame struct {
+	Kind    frameKind       `json:"kind"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// maxFrameBytes bounds a single frame's JSON payload to guard against a
+// misbehaving peer exhausting memory.
+const maxFrameBytes = 1 << 20
+
+// AuthPayload is sent by the client immediately after connecting.
+type AuthPayload struct {
+	Token string `json:"token"`
+}
+
+// ProbePayload describes the request the client wants the server to
+// perform on its behalf.
+type ProbePayload struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// EventPayload mirrors one of the progress events reach prints locally,
+// so the remote vantage point's phases show up in the local terminal.
+type EventPayload struct {
+	Name string `json:"name"`
+}
+
+// ResponsePayload carries the final status of the probed request.
+type ResponsePayload struct {
+	StatusCode int    `json:"status_code"`
+	Status     string `json:"status"`
+	Location   string `json:"location,omitempty"`
+}
+
+// ErrorPayload carries a failure that prevented the probe from
+// completing.
+type ErrorPayload struct {
+	Message string `json:"message"`
+}
+
+func writeFrame(w io.Writer, kind frameKind, payload interface{}) error {
+	encodedPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding %s frame: %w", kind, err)
+	}
+
+	encodedFrame, err := json.Marshal(frame{Kind: kind, Payload: encodedPayload})
+	if err != nil {
+		return fmt.Errorf("encoding %s frame: %w", kind, err)
+	}
+
+	if len(encodedFrame) > maxFrameBytes {
+		return fmt.Errorf("%s frame of %d bytes exceeds the %d byte limit", kind, len(encodedFrame), maxFrameBytes)
+	}
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(encodedFrame)))
+
+	if _, err := w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(encodedFrame)
+	return err
+}
+
+func readFrame(r io.Reader) (frame, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return frame{}, err
+	}
+
+	size := binary.BigEndian.Uint32(length[:])
+	if size > maxFrameBytes {
+		return frame{}, fmt.Errorf("frame of %d bytes exceeds the %d byte limit", size, maxFrameBytes)
+	}
+
+	encodedFrame := make([]byte, size)
+	if _, err := io.ReadFull(r, encodedFrame); err != nil {
+		return frame{}, err
+	}
+
+	var f frame
+	if err := json.Unmarshal(encodedFrame, &f); err != nil {
+		return frame{}, fmt.Errorf("decoding frame: %w", err)
+	}
+	return f, nil
+}
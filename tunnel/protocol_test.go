@@ -0,0 +1,59 @@
+package tunnel
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWriteReadFrameRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeFrame(&buf, frameKindProbe, ProbePayload{Method: "GET", URL: "https://example.com"}); err != nil {
+		t.Fatalf("writeFrame: unexpected error: %v", err)
+	}
+
+	f, err := readFrame(&buf)
+	if err != nil {
+		t.Fatalf("readFrame: unexpected error: %v", err)
+	}
+	if f.Kind != frameKindProbe {
+		t.Errorf("got kind %q, want %q", f.Kind, frameKindProbe)
+	}
+
+	var probe ProbePayload
+	if err := json.Unmarshal(f.Payload, &probe); err != nil {
+		t.Fatalf("unmarshaling payload: %v", err)
+	}
+	if probe.Method != "GET" || probe.URL != "https://example.com" {
+		t.Errorf("got %+v, want {GET https://example.com}", probe)
+	}
+}
+
+func TestReadFrameOversized(t *testing.T) {
+	var buf bytes.Buffer
+	var length [4]byte
+	length[0] = 0xff
+	length[1] = 0xff
+	length[2] = 0xff
+	length[3] = 0xff
+	buf.Write(length[:])
+
+	if _, err := readFrame(&buf); err == nil {
+		t.Error("expected error for oversized frame, got nil")
+	}
+}
+
+func TestWriteFrameOversized(t *testing.T) {
+	huge := strings.Repeat("a", maxFrameBytes+1)
+	err := writeFrame(&bytes.Buffer{}, frameKindEvent, EventPayload{Name: huge})
+	if err == nil {
+		t.Error("expected error for oversized frame, got nil")
+	}
+}
+
+func TestReadFrameShortRead(t *testing.T) {
+	if _, err := readFrame(bytes.NewReader([]byte{0, 0})); err == nil {
+		t.Error("expected error for truncated length prefix, got nil")
+	}
+}
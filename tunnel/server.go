@@ -0,0 +1,108 @@
+package tunnel
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// Serve listens on listenAddr and answers probe requests from reach --via
+// clients that present token. Each connection handles exactly one probe
+// before closing, matching reach's own one-shot request model. timeout
+// bounds both how long a client may take to authenticate and send its
+// probe, and how long the relayed request itself may run, so a stalled
+// client or an unresponsive target can't pin down a connection forever.
+func Serve(listenAddr string, token string, cert tls.Certificate, timeout time.Duration) error {
+	listener, err := tls.Listen("tcp", listenAddr, &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		return fmt.Errorf("listening on '%s': %w", listenAddr, err)
+	}
+	defer listener.Close()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return fmt.Errorf("accepting connection: %w", err)
+		}
+		go handleConn(conn, token, timeout)
+	}
+}
+
+func handleConn(conn net.Conn, token string, timeout time.Duration) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	authFrame, err := readFrame(conn)
+	if err != nil || authFrame.Kind != frameKindAuth {
+		return
+	}
+	var auth AuthPayload
+	if err := json.Unmarshal(authFrame.Payload, &auth); err != nil {
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(auth.Token), []byte(token)) != 1 {
+		writeFrame(conn, frameKindError, ErrorPayload{Message: "invalid token"})
+		return
+	}
+
+	probeFrame, err := readFrame(conn)
+	if err != nil || probeFrame.Kind != frameKindProbe {
+		return
+	}
+	var probe ProbePayload
+	if err := json.Unmarshal(probeFrame.Payload, &probe); err != nil {
+		writeFrame(conn, frameKindError, ErrorPayload{Message: err.Error()})
+		return
+	}
+
+	performProbe(conn, probe, timeout)
+}
+
+// performProbe runs the requested method/URL locally, relaying each
+// httptrace event to conn as it happens and finishing with either a
+// response or error frame. timeout bounds the relayed request so a
+// non-responding target can't hold the connection open past conn's own
+// deadline.
+func performProbe(conn net.Conn, probe ProbePayload, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, probe.Method, probe.URL, nil)
+	if err != nil {
+		writeFrame(conn, frameKindError, ErrorPayload{Message: err.Error()})
+		return
+	}
+
+	emit := func(name string) {
+		writeFrame(conn, frameKindEvent, EventPayload{Name: name})
+	}
+
+	trace := &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { emit("Starting DNS Lookup") },
+		DNSDone:              func(httptrace.DNSDoneInfo) { emit("DNS Lookup Complete") },
+		ConnectStart:         func(string, string) { emit("Connection Started") },
+		ConnectDone:          func(string, string, error) { emit("Connected - waiting for response...") },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { emit("TLS Handshake Complete.") },
+		GotFirstResponseByte: func() { emit("Receiving Response") },
+	}
+	request = request.WithContext(httptrace.WithClientTrace(request.Context(), trace))
+
+	response, err := http.DefaultTransport.RoundTrip(request)
+	if err != nil {
+		writeFrame(conn, frameKindError, ErrorPayload{Message: err.Error()})
+		return
+	}
+	defer response.Body.Close()
+
+	writeFrame(conn, frameKindResponse, ResponsePayload{
+		StatusCode: response.StatusCode,
+		Status:     response.Status,
+		Location:   response.Header.Get("Location"),
+	})
+}